@@ -0,0 +1,65 @@
+// Package bridge forwards chat traffic to and from remote gateways (IRC,
+// Matrix, Rocket.Chat webhooks, ...), following the pattern where each
+// protocol runs its own goroutine against the remote transport and
+// normalizes everything to a single Message shape before handing it to the
+// rest of the system.
+package bridge
+
+import (
+	"fmt"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("http-chat-client/bridge")
+
+// Message is the normalized shape every Bridge implementation speaks,
+// regardless of which remote protocol produced or will carry it.
+type Message struct {
+	// Username is the sender as known on the remote side, e.g. "alice".
+	Username string
+	// Text is the message body.
+	Text string
+	// RemoteRoom is the channel/room the message came from or is bound for.
+	RemoteRoom string
+}
+
+// Bridge connects one remote chat network to the local http-chat room.
+type Bridge interface {
+	// Send forwards a local message out to the remote network.
+	Send(msg Message) error
+	// Receive returns the channel of messages read off the remote network.
+	// It is closed when the bridge's connection to the remote network ends.
+	Receive() <-chan Message
+}
+
+// Config describes a single bridge to start, as read from ChatConfig.
+type Config struct {
+	Protocol string `json:"protocol"`
+	// GatewayID is a public, non-secret identifier for this bridge (used,
+	// for example, as the IRC nick and as the "[gatewayID]username" prefix
+	// bridged messages carry into the local room). It must never hold a
+	// credential: it ends up in chat history visible to every client.
+	GatewayID string `json:"gateway-id"`
+	// Endpoint is the non-secret connection target: an "host:port" for
+	// IRC, a homeserver base URL for Matrix, a webhook URL for Rocket.Chat.
+	Endpoint string `json:"endpoint"`
+	// Credentials is the secret for this bridge (an IRC server password, a
+	// Matrix access token, ...). Never log it or fold it into GatewayID.
+	Credentials string `json:"credentials"`
+	RemoteRoom  string `json:"remote-room"`
+}
+
+// New constructs the Bridge implementation named by cfg.Protocol.
+func New(cfg Config) (Bridge, error) {
+	switch cfg.Protocol {
+	case "irc":
+		return newIRCBridge(cfg), nil
+	case "matrix":
+		return newMatrixBridge(cfg), nil
+	case "webhook":
+		return newWebhookBridge(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported bridge protocol %q for gateway %q", cfg.Protocol, cfg.GatewayID)
+	}
+}
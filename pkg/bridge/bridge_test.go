@@ -0,0 +1,80 @@
+package bridge
+
+import "testing"
+
+func TestParsePRIVMSG(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		wantUsername string
+		wantText     string
+		wantOK       bool
+	}{
+		{
+			name:         "well formed PRIVMSG",
+			line:         ":alice!a@host PRIVMSG #room :hello there",
+			wantUsername: "alice",
+			wantText:     "hello there",
+			wantOK:       true,
+		},
+		{
+			name:   "not a PRIVMSG is ignored",
+			line:   ":irc.server.net 001 alice :Welcome",
+			wantOK: false,
+		},
+		{
+			name:   "missing nick/host separator",
+			line:   "alicePRIVMSG #room :hi",
+			wantOK: false,
+		},
+		{
+			name:   "missing trailing colon is not parsed",
+			line:   ":alice!a@host PRIVMSG #room hi",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username, text, ok := parsePRIVMSG(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("parsePRIVMSG(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if username != tt.wantUsername || text != tt.wantText {
+				t.Fatalf("parsePRIVMSG(%q) = (%q, %q), want (%q, %q)",
+					tt.line, username, text, tt.wantUsername, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestNewRejectsUnknownProtocol(t *testing.T) {
+	_, err := New(Config{Protocol: "carrier-pigeon", GatewayID: "test"})
+	if err == nil {
+		t.Fatal("expected an error for an unsupported protocol")
+	}
+}
+
+func TestNewDispatchesKnownProtocols(t *testing.T) {
+	for _, protocol := range []string{"irc", "matrix", "webhook"} {
+		t.Run(protocol, func(t *testing.T) {
+			b, err := New(Config{Protocol: protocol, GatewayID: "test", Endpoint: "irc.example.org:6667", RemoteRoom: "#room"})
+			if err != nil {
+				t.Fatalf("New(%q) returned unexpected error: %v", protocol, err)
+			}
+			if b == nil {
+				t.Fatalf("New(%q) returned a nil Bridge", protocol)
+			}
+		})
+	}
+}
+
+func TestIRCBridgeSendRequiresConnection(t *testing.T) {
+	b := &ircBridge{cfg: Config{GatewayID: "test"}, recv: make(chan Message, 1)}
+	if err := b.Send(Message{Username: "alice", Text: "hi"}); err == nil {
+		t.Fatal("expected Send to fail before the bridge has connected")
+	}
+}
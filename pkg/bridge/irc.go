@@ -0,0 +1,113 @@
+package bridge
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// ircBridge relays messages to and from a single IRC channel over a raw IRC
+// connection.
+type ircBridge struct {
+	cfg  Config
+	recv chan Message
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newIRCBridge(cfg Config) *ircBridge {
+	b := &ircBridge{cfg: cfg, recv: make(chan Message, 32)}
+	go b.handleIRC()
+	return b
+}
+
+// setConn and getConn guard conn, which handleIRC writes once the dial
+// succeeds and Send reads from a different goroutine.
+func (b *ircBridge) setConn(conn net.Conn) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.conn = conn
+}
+
+func (b *ircBridge) getConn() net.Conn {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.conn
+}
+
+// handleIRC dials the remote IRC server, joins the configured room, and
+// normalizes every PRIVMSG it sees into a Message on the Receive channel.
+// It keeps running until the connection is lost (or the dial fails), and
+// then closes the Receive channel.
+func (b *ircBridge) handleIRC() {
+	defer close(b.recv)
+
+	conn, err := net.Dial("tcp", b.cfg.Endpoint)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to dial IRC gateway %q", b.cfg.GatewayID)
+		return
+	}
+	b.setConn(conn)
+	defer conn.Close()
+
+	if b.cfg.Credentials != "" {
+		fmt.Fprintf(conn, "PASS %s\r\n", b.cfg.Credentials)
+	}
+	fmt.Fprintf(conn, "NICK %s\r\n", b.cfg.GatewayID)
+	fmt.Fprintf(conn, "USER %s 0 * :http-chat-client bridge\r\n", b.cfg.GatewayID)
+	fmt.Fprintf(conn, "JOIN %s\r\n", b.cfg.RemoteRoom)
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "PING") {
+			fmt.Fprintf(conn, "PONG%s\r\n", strings.TrimPrefix(line, "PING"))
+			continue
+		}
+		username, text, ok := parsePRIVMSG(line)
+		if !ok {
+			continue
+		}
+		b.recv <- Message{Username: username, Text: text, RemoteRoom: b.cfg.RemoteRoom}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Error().Err(err).Msgf("IRC gateway %q connection lost", b.cfg.GatewayID)
+	}
+}
+
+// parsePRIVMSG extracts the sender nick and text from a raw ":nick!user@host
+// PRIVMSG #room :text" IRC line.
+func parsePRIVMSG(line string) (username string, text string, ok bool) {
+	if !strings.Contains(line, "PRIVMSG") {
+		return "", "", false
+	}
+	prefix, rest, found := strings.Cut(strings.TrimPrefix(line, ":"), " ")
+	if !found {
+		return "", "", false
+	}
+	username, _, found = strings.Cut(prefix, "!")
+	if !found {
+		return "", "", false
+	}
+	_, msgPart, found := strings.Cut(rest, " :")
+	if !found {
+		return "", "", false
+	}
+	return username, msgPart, true
+}
+
+func (b *ircBridge) Send(msg Message) error {
+	conn := b.getConn()
+	if conn == nil {
+		return fmt.Errorf("IRC gateway %q is not connected", b.cfg.GatewayID)
+	}
+	_, err := fmt.Fprintf(conn, "PRIVMSG %s :<%s> %s\r\n", b.cfg.RemoteRoom, msg.Username, msg.Text)
+	return err
+}
+
+func (b *ircBridge) Receive() <-chan Message {
+	return b.recv
+}
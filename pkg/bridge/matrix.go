@@ -0,0 +1,111 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// matrixBridge relays messages to and from a Matrix room by long-polling
+// the homeserver's /sync endpoint and POSTing outbound messages via
+// /send.
+type matrixBridge struct {
+	cfg        Config
+	httpClient *http.Client
+	recv       chan Message
+}
+
+func newMatrixBridge(cfg Config) *matrixBridge {
+	b := &matrixBridge{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		recv:       make(chan Message, 32),
+	}
+	go b.handleMatrix()
+	return b
+}
+
+// handleMatrix long-polls the homeserver's /sync endpoint and normalizes
+// every m.room.message event for the configured room into a Message.
+func (b *matrixBridge) handleMatrix() {
+	defer close(b.recv)
+
+	since := ""
+	for {
+		url := fmt.Sprintf("%s/_matrix/client/r0/sync?since=%s&timeout=30000",
+			b.cfg.Endpoint, since)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			log.Error().Err(err).Msgf("Matrix gateway %q sync failed", b.cfg.GatewayID)
+			return
+		}
+		req.Header.Set("Authorization", "Bearer "+b.cfg.Credentials)
+		resp, err := b.httpClient.Do(req)
+		if err != nil {
+			log.Error().Err(err).Msgf("Matrix gateway %q sync failed", b.cfg.GatewayID)
+			return
+		}
+		var sync struct {
+			NextBatch string `json:"next_batch"`
+			Rooms     struct {
+				Join map[string]struct {
+					Timeline struct {
+						Events []struct {
+							Type    string `json:"type"`
+							Sender  string `json:"sender"`
+							Content struct {
+								Body string `json:"body"`
+							} `json:"content"`
+						} `json:"events"`
+					} `json:"timeline"`
+				} `json:"join"`
+			} `json:"rooms"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&sync)
+		resp.Body.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Matrix gateway %q failed to decode /sync response", b.cfg.GatewayID)
+			return
+		}
+		since = sync.NextBatch
+
+		if room, ok := sync.Rooms.Join[b.cfg.RemoteRoom]; ok {
+			for _, event := range room.Timeline.Events {
+				if event.Type != "m.room.message" {
+					continue
+				}
+				b.recv <- Message{Username: event.Sender, Text: event.Content.Body, RemoteRoom: b.cfg.RemoteRoom}
+			}
+		}
+	}
+}
+
+func (b *matrixBridge) Send(msg Message) error {
+	url := fmt.Sprintf("%s/_matrix/client/r0/rooms/%s/send/m.room.message",
+		b.cfg.Endpoint, b.cfg.RemoteRoom)
+	body, _ := json.Marshal(map[string]string{
+		"msgtype": "m.text",
+		"body":    fmt.Sprintf("<%s> %s", msg.Username, msg.Text),
+	})
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.cfg.Credentials)
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("matrix gateway %q /send returned status %d", b.cfg.GatewayID, resp.StatusCode)
+	}
+	return nil
+}
+
+func (b *matrixBridge) Receive() <-chan Message {
+	return b.recv
+}
@@ -0,0 +1,46 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// webhookBridge relays outbound messages to a Rocket.Chat-style incoming
+// webhook URL. Webhooks are push-only on the remote side, so Receive never
+// yields anything; it only exists to satisfy the Bridge interface.
+type webhookBridge struct {
+	cfg        Config
+	httpClient *http.Client
+	recv       chan Message
+}
+
+func newWebhookBridge(cfg Config) *webhookBridge {
+	return &webhookBridge{
+		cfg:        cfg,
+		httpClient: &http.Client{},
+		recv:       make(chan Message),
+	}
+}
+
+func (b *webhookBridge) Send(msg Message) error {
+	body, _ := json.Marshal(map[string]string{
+		"username": msg.Username,
+		"text":     msg.Text,
+		"channel":  b.cfg.RemoteRoom,
+	})
+	resp, err := b.httpClient.Post(b.cfg.Endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("webhook gateway %q returned status %d", b.cfg.GatewayID, resp.StatusCode)
+	}
+	return nil
+}
+
+func (b *webhookBridge) Receive() <-chan Message {
+	return b.recv
+}
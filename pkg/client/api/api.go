@@ -0,0 +1,131 @@
+// Package api exposes the chat room as a versioned JSON REST API, so
+// non-browser clients (mobile apps, bots, the bridge subsystem) don't have
+// to scrape the HTML handlers in pkg/client.
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/draychev/http-chat-client/pkg/client"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("http-chat-client/api")
+
+// --- /api/v1 Endpoints
+const (
+	EndPointMessages = "/api/v1/messages"
+	EndPointUsers    = "/api/v1/users"
+	EndPointMe       = "/api/v1/me"
+	EndPointFilters  = "/api/v1/filters"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// responseOK writes v as a 200 JSON response.
+func responseOK(w http.ResponseWriter, v interface{}) {
+	writeJSON(w, http.StatusOK, v)
+}
+
+// responseCreated writes v as a 201 JSON response.
+func responseCreated(w http.ResponseWriter, v interface{}) {
+	writeJSON(w, http.StatusCreated, v)
+}
+
+// defaultErrorHandler writes err as a JSON error body under status.
+func defaultErrorHandler(w http.ResponseWriter, status int, err error) {
+	log.Error().Err(err).Msgf("API request failed with status %d", status)
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// newRequestID returns a short random hex string used to correlate a
+// request across logs.
+func newRequestID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// withRequestID stamps every response with an X-Request-ID header before
+// handing off to next.
+func withRequestID(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIDHeader, newRequestID())
+		next(w, r)
+	}
+}
+
+// HandlerMessages dispatches GET (list messages) and POST (send a message)
+// on the same /api/v1/messages resource.
+func HandlerMessages(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		responseOK(w, client.GetMessages())
+	case http.MethodPost:
+		handlerSendMessage(w, r)
+	default:
+		defaultErrorHandler(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed on %s", r.Method, EndPointMessages))
+	}
+}
+
+func handlerSendMessage(w http.ResponseWriter, r *http.Request) {
+	var msg client.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		defaultErrorHandler(w, http.StatusBadRequest, fmt.Errorf("invalid message body: %w", err))
+		return
+	}
+	if err := client.SendMessage(msg.Message); err != nil {
+		defaultErrorHandler(w, http.StatusBadGateway, fmt.Errorf("failed to send the message: %w", err))
+		return
+	}
+	responseCreated(w, msg)
+}
+
+// HandlerUsers returns the current roster.
+func HandlerUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		defaultErrorHandler(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed on %s", r.Method, EndPointUsers))
+		return
+	}
+	responseOK(w, client.GetActiveUsers())
+}
+
+// HandlerMe returns the identity of this running chat client.
+func HandlerMe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		defaultErrorHandler(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed on %s", r.Method, EndPointMe))
+		return
+	}
+	responseOK(w, client.Me())
+}
+
+// HandlerFilters returns the currently active filter ruleset, so operators
+// can inspect what's being dropped/redacted/tagged without reading the
+// config file.
+func HandlerFilters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		defaultErrorHandler(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed on %s", r.Method, EndPointFilters))
+		return
+	}
+	responseOK(w, client.FilterRules())
+}
+
+// RegisterHandlers mounts the /api/v1 handlers on mux, each stamped with an
+// X-Request-ID header.
+func RegisterHandlers(mux *http.ServeMux) {
+	mux.HandleFunc(EndPointMessages, withRequestID(HandlerMessages))
+	mux.HandleFunc(EndPointUsers, withRequestID(HandlerUsers))
+	mux.HandleFunc(EndPointMe, withRequestID(HandlerMe))
+	mux.HandleFunc(EndPointFilters, withRequestID(HandlerFilters))
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/draychev/http-chat-client/pkg/bridge"
+)
+
+// bridgePrefix marks a message as having already crossed a bridge once, so
+// it's never forwarded a second time (loop prevention between bridges and
+// between a bridge and itself).
+func bridgePrefix(gatewayID string) string {
+	return fmt.Sprintf("[%s]", gatewayID)
+}
+
+// startBridge wires one configured remote gateway into the local room: a
+// goroutine posts everything the bridge receives into the local room under
+// a synthetic prefixed username, and a second goroutine forwards new local
+// messages out to the bridge.
+func startBridge(cfg bridge.Config) {
+	b, err := bridge.New(cfg)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to start bridge for gateway %q", cfg.GatewayID)
+		return
+	}
+
+	go forwardInbound(cfg, b)
+	go forwardOutbound(cfg, b)
+}
+
+// forwardInbound reads messages the bridge receives from the remote
+// network and posts them into the local room under the synthetic
+// "[gateway]username" identity, so forwardOutbound can recognize and skip
+// them on the next tick.
+func forwardInbound(cfg bridge.Config, b bridge.Bridge) {
+	prefix := bridgePrefix(cfg.GatewayID)
+	for msg := range b.Receive() {
+		_ = sendMessageAs(prefix+msg.Username, msg.Text)
+	}
+	log.Info().Msgf("Bridge %q inbound connection closed", cfg.GatewayID)
+}
+
+// forwardOutbound polls the local room for messages sent since the last
+// one it forwarded and sends each of them out through the bridge, skipping
+// any message that already carries a bridge prefix so bridged traffic
+// never bounces back out the way it came in. lastSeen starts at the
+// current time rather than the zero value, so startup doesn't replay the
+// room's entire history out to the bridge.
+func forwardOutbound(cfg bridge.Config, b bridge.Bridge) {
+	lastSeen := time.Now()
+	ticker := time.NewTicker(3000 * time.Millisecond)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for _, msg := range getMessages() {
+			if !msg.TimeSent.After(lastSeen) {
+				continue
+			}
+			lastSeen = msg.TimeSent
+			if isBridgedUsername(msg.Username) {
+				continue
+			}
+			if err := b.Send(bridge.Message{Username: msg.Username, Text: msg.Message, RemoteRoom: cfg.RemoteRoom}); err != nil {
+				log.Error().Err(err).Msgf("Failed to forward message to bridge %q", cfg.GatewayID)
+			}
+		}
+	}
+}
+
+// isBridgedUsername reports whether username already carries a
+// "[gateway]" prefix, meaning it arrived via some bridge and must not be
+// forwarded back out to prevent message loops.
+func isBridgedUsername(username string) bool {
+	return strings.HasPrefix(username, "[") && strings.Contains(username, "]")
+}
@@ -1,7 +1,6 @@
 package client
 
 import (
-	"bytes"
 	_ "embed"
 	"encoding/json"
 	"fmt"
@@ -10,6 +9,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/draychev/http-chat-client/pkg/bridge"
+	"github.com/draychev/http-chat-client/pkg/client/filter"
+
 	"github.com/openservicemesh/osm/pkg/logger"
 )
 
@@ -20,6 +22,16 @@ var log = logger.New("http-chat-client")
 
 var Config *ChatConfig
 
+// filterChain holds the active blocklists and filter rules. It starts out
+// empty (Apply is a no-op until NewChatClient populates it from Config) so
+// that code paths running before startup never see a nil chain.
+var filterChain, _ = filter.NewChain(nil, nil, nil)
+
+// activeTransport is how this package talks to the upstream chat server.
+// NewChatClient replaces it with options.Transport, or a default
+// httpTransport built from the rest of Options.
+var activeTransport Transport = newHTTPTransport(defaultOptions())
+
 // --- Web ChatServerFQDN Endpoints
 const (
 	EndPointSendMessage = "/send-message"
@@ -63,9 +75,23 @@ type Ping struct {
 
 // ChatConfig keeps the config needed to connect to the HTTPChat network
 type ChatConfig struct {
-	ChatServerFQDN      string `json:"chat-server-fqdn"`
-	ChatServerPort      int    `json:"chat-server-port"`
-	WebServerPortNumber int    `json:"web-server-port-number"`
+	ChatServerFQDN      string          `json:"chat-server-fqdn"`
+	ChatServerPort      int             `json:"chat-server-port"`
+	WebServerPortNumber int             `json:"web-server-port-number"`
+	Bridges             []bridge.Config `json:"bridges"`
+	BlockedUsers        []string        `json:"blocked-users"`
+	BlockedPatterns     []string        `json:"blocked-patterns"`
+	Filters             []filter.Rule   `json:"filters"`
+	// LegacyMode keeps the old meta-refresh polling endpoints
+	// (EndPointGetMessages, EndPointGetUsers) reachable for clients that
+	// haven't moved to the /ws live-update transport yet.
+	//
+	// This is a ChatConfig field rather than a "--legacy" CLI flag: the
+	// client is built around functional options and an activeTransport
+	// that tests swap out from under the package, and a global `flag`
+	// would fight both. Toggling it with the rest of the JSON config
+	// keeps it in line with every other per-deployment setting above.
+	LegacyMode bool `json:"legacy-mode"`
 }
 
 func readConfig(fileName string) *ChatConfig {
@@ -108,16 +134,20 @@ func HandlerSendMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	message := r.Form.Get(formKeyMessage)
-	sendMessage(message)
+	_ = sendMessage(message)
 	http.Redirect(w, r, "/", 302)
 }
 
 func HandlerGetMessages(w http.ResponseWriter, r *http.Request) {
 	var messages []string
 	for idx, msg := range getMessages() {
+		text, keep := filterChain.Apply(filter.ScopeInbound, msg.Username, msg.Message)
+		if !keep {
+			continue
+		}
 		messages = append(
 			messages,
-			fmt.Sprintf("(%d)[%s] %s", idx, msg.Username, msg.Message),
+			fmt.Sprintf("(%d)[%s] %s", idx, msg.Username, text),
 		)
 	}
 	content := `<!doctype html><html itemscope="" itemtype="http://schema.org/WebPage" lang="en">
@@ -148,121 +178,213 @@ func HandlerIndex(w http.ResponseWriter, r *http.Request) {
 	content := `<!doctype html><html itemscope="" itemtype="http://schema.org/WebPage" lang="en">
 	<head><title>http-chat-client is awesome</title><style></style>` + getCSS() + `</head><body>
       <table><tr><td>
-      <iframe marginwidth="0" marginheight="0" width="480" height="640" scrolling="yes" frameborder=0 src="` + EndPointGetMessages + `">
-      </iframe>
+      <strong>Chat Messages:</strong>
+      <div id="messages"></div>
       </td><td>
-      <iframe marginwidth="0" marginheight="0" width="480" height="640" scrolling="yes" frameborder=0 src="` + EndPointGetUsers + `">
-      </iframe>
+      <strong>Users:</strong>
+      <div id="users"></div>
       </td></tr></table>
-      <form method="post" action="` + EndPointSendMessage + `">
-        <input type="text" id="` + formKeyMessage + `" name="` + formKeyMessage + `" />
-        <input type="submit" value="Send" />
-      </form></body></html>`
+      <input type="text" id="` + formKeyMessage + `" />
+      <input type="submit" value="Send" onclick="sendMessage()" />
+      <script>` + wsClientJS() + `</script>
+      </body></html>`
 	_, _ = fmt.Fprintf(w, "%s", content)
 }
 
-func sendMessage(message string) {
-	log.Info().Msgf("Sending message: %s", message)
-	httpClient := &http.Client{}
-	jsonBytes, _ := json.Marshal(Message{Username: EnvVarUserName, Message: message})
-	url := fmt.Sprintf("http://%s:%d/messages", Config.ChatServerFQDN, Config.ChatServerPort)
-	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(jsonBytes))
-	if err != nil {
-		log.Error().Err(err).Msgf("Failed to POST message to %s: %s", url, jsonBytes)
-		return
+// wsClientJS is the small inline client that opens the /ws connection once
+// and appends incoming message events and redraws the user list on user
+// events, instead of reloading iframes on a timer.
+func wsClientJS() string {
+	return `
+      var proto = window.location.protocol === "https:" ? "wss://" : "ws://";
+      var socket = new WebSocket(proto + window.location.host + "` + EndPointWebSocket + `");
+      socket.onmessage = function(event) {
+        var frame = JSON.parse(event.data);
+        if (frame.type === "message") {
+          var messages = document.getElementById("messages");
+          messages.innerHTML += "[" + frame.data.username + "] " + frame.data.message + "<br/>";
+        } else if (frame.type === "users") {
+          var users = document.getElementById("users");
+          users.innerHTML = frame.data.map(function(u) { return u.username; }).join("<br/>");
+        }
+      };
+      function sendMessage() {
+        var input = document.getElementById("` + formKeyMessage + `");
+        fetch("/api/v1/messages", {
+          method: "POST",
+          headers: {"Content-Type": "application/json"},
+          body: JSON.stringify({username: "` + EnvVarUserName + `", message: input.value})
+        });
+        input.value = "";
+      };
+`
+}
+
+func sendMessage(message string) error {
+	text, keep := filterChain.Apply(filter.ScopeOutbound, EnvVarUserName, message)
+	if !keep {
+		return fmt.Errorf("message blocked by an outbound filter rule")
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		log.Error().Msgf("Failed to POST message to %s, status code: %d", url, resp.StatusCode)
-		return
+
+	log.Info().Msgf("Sending message: %s", text)
+	if err := activeTransport.SendMessage(Message{Username: EnvVarUserName, Message: text}); err != nil {
+		log.Error().Err(err).Msg("Failed to send message")
+		return err
 	}
+	return nil
+}
+
+// sendMessageAs sends text under an explicit username rather than
+// EnvVarUserName, bypassing the outbound filter chain (that chain guards
+// what this client's own user says, not traffic relayed in from a
+// bridge). It's how pkg/bridge messages keep their "[gateway]username"
+// identity instead of being attributed to this bot's own username.
+func sendMessageAs(username, text string) error {
+	log.Info().Msgf("Sending message as %s: %s", username, text)
+	if err := activeTransport.SendMessage(Message{Username: username, Message: text}); err != nil {
+		log.Error().Err(err).Msgf("Failed to send message as %s", username)
+		return err
+	}
+	return nil
+}
+
+// SendMessage sends message as this client's user. It returns an error if an
+// outbound filter rule blocks the message or if the upstream chat server
+// does not accept it. It is exported for the JSON API
+// handlers in pkg/client/api, which need the same error to translate into
+// an HTTP status code.
+func SendMessage(message string) error {
+	return sendMessage(message)
+}
+
+// GetMessages returns the current message history from the upstream chat
+// server with inbound filters applied. Exported for pkg/client/api's GET
+// /api/v1/messages handler.
+func GetMessages() []Message {
+	messages := getMessages()
+	filtered := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		text, keep := filterChain.Apply(filter.ScopeInbound, msg.Username, msg.Message)
+		if !keep {
+			continue
+		}
+		msg.Message = text
+		filtered = append(filtered, msg)
+	}
+	return filtered
+}
+
+// GetActiveUsers returns the current roster from the upstream chat server.
+// Exported for pkg/client/api.
+func GetActiveUsers() []*User {
+	return getActiveUsers()
+}
+
+// Me returns the identity of this running chat client.
+func Me() User {
+	return User{Username: EnvVarUserName, LastPing: time.Now()}
+}
+
+// reloadFilterChain recompiles filterChain from the current Config. It is
+// called once at startup and again every time the config file watcher
+// fires.
+func reloadFilterChain() error {
+	return filterChain.Reload(Config.BlockedUsers, Config.BlockedPatterns, Config.Filters)
+}
+
+// FilterRules returns the currently active filter rules. Exported for
+// pkg/client/api's /api/v1/filters endpoint.
+func FilterRules() []filter.Rule {
+	return filterChain.Rules()
 }
 
 func getMessages() []Message {
 	log.Info().Msg("Getting the list of messages...")
-	httpClient := &http.Client{}
-	var messages []Message
-	// get messages
-	url := fmt.Sprintf("http://%s:%d/messages", Config.ChatServerFQDN, Config.ChatServerPort)
-	resp, err := httpClient.Get(url)
+	messages, err := activeTransport.GetMessages()
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get messages")
-		return messages
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		log.Error().Msgf("Failed to get messages, status code: %d", resp.StatusCode)
-		return messages
-	}
-	err = json.NewDecoder(resp.Body).Decode(&messages)
-	if err != nil {
-		log.Error().Err(err).Msgf("Failed to decode messages: %+v", messages)
-		return messages
+		return nil
 	}
 	return messages
 }
 
 func sendPing() {
-	httpClient := &http.Client{}
 	ping := Ping{Username: EnvVarUserName}
-	jsonBytes, _ := json.Marshal(ping)
-	url := fmt.Sprintf("http://%s:%d/ping", Config.ChatServerFQDN, Config.ChatServerPort)
-	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(jsonBytes))
-	if err != nil {
-		log.Error().Err(err).Msgf("Failed to send a ping to : %s", url)
-		return
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		log.Error().Msgf("Failed to send ping to %s, status code: %d", url, resp.StatusCode)
+	if err := activeTransport.SendPing(ping); err != nil {
+		log.Error().Err(err).Msg("Failed to send a ping")
 		return
 	}
 	log.Info().Msgf("Sent a PING: %s", ping)
 }
 
 func getActiveUsers() []*User {
-	httpClient := &http.Client{}
-	var users []*User
-	url := fmt.Sprintf("http://%s:%d/users", Config.ChatServerFQDN, Config.ChatServerPort)
-	resp, err := httpClient.Get(url)
-	if err != nil {
-		log.Error().Err(err).Msgf("Failed to get active users from %s", url)
-		return users
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		log.Error().Msgf("Failed to get active users from %s, status code: %d", url, resp.StatusCode)
-		return users
-	}
-	err = json.NewDecoder(resp.Body).Decode(&users)
+	users, err := activeTransport.GetActiveUsers()
 	if err != nil {
-		log.Error().Err(err).Msgf("Failed to decode active users from %s: %v", url, err)
-		return users
+		log.Error().Err(err).Msg("Failed to get active users")
+		return nil
 	}
 	return users
 }
 
-func NewChatClient(quit chan interface{}, ready chan interface{}) {
+// NewChatClient starts the chat client: it loads config, wires up the
+// transport opts describe and the filters from the config file, registers
+// the HTTP handlers, and
+// blocks serving until the web server exits. The default Options (no opts
+// given) preserve this package's historical behavior: a bare *http.Client
+// talking plain HTTP, no retries, no auth.
+func NewChatClient(opts ...Option) error {
 	for _, key := range []string{EnvVarUserNameKey, EnvVarConfigFileNameKey} {
 		if os.Getenv(key) == "" {
 			log.Fatal().Msgf("Environment variable %s is required", key)
 		}
 	}
 
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	log = options.Logger
+	if options.Transport != nil {
+		activeTransport = options.Transport
+	} else {
+		activeTransport = newHTTPTransport(options)
+	}
+
 	Config = readConfig(EnvVarConfigFileName)
 
+	if err := reloadFilterChain(); err != nil {
+		log.Error().Err(err).Msg("Failed to load filters from config, continuing with no filters")
+	}
+	go func() {
+		if err := filter.Watch(EnvVarConfigFileName, func() {
+			Config = readConfig(EnvVarConfigFileName)
+			if err := reloadFilterChain(); err != nil {
+				log.Error().Err(err).Msg("Failed to hot-reload filters from config")
+			}
+		}); err != nil {
+			log.Error().Err(err).Msg("Filter config watcher stopped")
+		}
+	}()
+
 	http.HandleFunc("/", HandlerIndex)
-	http.HandleFunc(EndPointGetMessages, HandlerGetMessages)
-	http.HandleFunc(EndPointGetUsers, HandlerGetUsers)
+	http.HandleFunc(EndPointWebSocket, HandlerWebSocket)
 	http.HandleFunc(EndPointSendMessage, HandlerSendMessage)
+	if Config.LegacyMode {
+		log.Info().Msg("Legacy HTML polling endpoints enabled")
+		http.HandleFunc(EndPointGetMessages, HandlerGetMessages)
+		http.HandleFunc(EndPointGetUsers, HandlerGetUsers)
+	}
+
+	for _, bridgeCfg := range Config.Bridges {
+		startBridge(bridgeCfg)
+	}
 
 	ticker := time.NewTicker(3000 * time.Millisecond)
 	done := make(chan bool)
+	defer ticker.Stop()
 
-	defer func() {
-		ticker.Stop()
-		done <- true
-	}()
+	var lastMessageCount int
+	var lastUsers []*User
 
 	go func() {
 		for {
@@ -272,11 +394,49 @@ func NewChatClient(quit chan interface{}, ready chan interface{}) {
 			case _ = <-ticker.C:
 				log.Info().Msgf("Trying to send a PING %+v...", time.Now())
 				sendPing()
+
+				messages := getMessages()
+				if lastMessageCount > len(messages) {
+					lastMessageCount = 0
+				}
+				for _, msg := range messages[lastMessageCount:] {
+					text, keep := filterChain.Apply(filter.ScopeInbound, msg.Username, msg.Message)
+					if !keep {
+						continue
+					}
+					msg.Message = text
+					chatHub.broadcast(wsEvent{Type: wsEventTypeMessage, Data: msg})
+				}
+				lastMessageCount = len(messages)
+
+				users := getActiveUsers()
+				if usersChanged(lastUsers, users) {
+					chatHub.broadcast(wsEvent{Type: wsEventTypeUsers, Data: users})
+					lastUsers = users
+				}
 			}
 		}
 	}()
-	ready <- true
-	<-quit
+	defer close(done)
+
+	log.Info().Msgf("Starting chat client for user %s; Listening on port %d; connecting to server %s on port %d",
+		EnvVarUserName, Config.WebServerPortNumber, Config.ChatServerFQDN, Config.ChatServerPort)
+	return http.ListenAndServe(fmt.Sprintf(":%d", Config.WebServerPortNumber), nil)
+}
+
+// usersChanged reports whether the active-user snapshot differs from the
+// last one pushed to subscribers, so the ticker only broadcasts a "users"
+// frame when the roster actually changes.
+func usersChanged(previous, current []*User) bool {
+	if len(previous) != len(current) {
+		return true
+	}
+	for i, user := range current {
+		if previous[i].Username != user.Username {
+			return true
+		}
+	}
+	return false
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -0,0 +1,139 @@
+// Package filter applies operator-configured blacklists and rules to chat
+// traffic passing through pkg/client, in both directions.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// Action is what a matching Rule does to a message.
+type Action string
+
+const (
+	ActionDrop   Action = "drop"
+	ActionRedact Action = "redact"
+	ActionTag    Action = "tag"
+)
+
+// Scope selects which direction of traffic a Rule applies to.
+type Scope string
+
+const (
+	ScopeInbound  Scope = "inbound"
+	ScopeOutbound Scope = "outbound"
+	ScopeBoth     Scope = "both"
+)
+
+// Rule is one operator-configured filter, as read from ChatConfig.
+type Rule struct {
+	Match  string `json:"match"`
+	Action Action `json:"action"`
+	Scope  Scope  `json:"scope"`
+}
+
+type compiledRule struct {
+	rule    Rule
+	pattern *regexp.Regexp
+}
+
+// Chain is the active set of blocklists and rules. It is safe for
+// concurrent use; Reload atomically swaps in a new ruleset, which is how
+// the hot-reload watcher applies config-file edits without a restart.
+type Chain struct {
+	mu              sync.RWMutex
+	blockedUsers    map[string]bool
+	blockedPatterns []*regexp.Regexp
+	rules           []compiledRule
+}
+
+// NewChain compiles blockedUsers, blockedPatterns, and rules into a Chain.
+func NewChain(blockedUsers, blockedPatterns []string, rules []Rule) (*Chain, error) {
+	c := &Chain{}
+	if err := c.Reload(blockedUsers, blockedPatterns, rules); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload recompiles and atomically swaps in a new ruleset.
+func (c *Chain) Reload(blockedUsers, blockedPatterns []string, rules []Rule) error {
+	users := make(map[string]bool, len(blockedUsers))
+	for _, u := range blockedUsers {
+		users[u] = true
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(blockedPatterns))
+	for _, p := range blockedPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("invalid blocked pattern %q: %w", p, err)
+		}
+		patterns = append(patterns, re)
+	}
+
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, r := range rules {
+		re, err := regexp.Compile(r.Match)
+		if err != nil {
+			return fmt.Errorf("invalid filter rule match %q: %w", r.Match, err)
+		}
+		compiled = append(compiled, compiledRule{rule: r, pattern: re})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blockedUsers = users
+	c.blockedPatterns = patterns
+	c.rules = compiled
+	return nil
+}
+
+// Apply runs username/text through the blocklists and rules that match
+// scope. It returns the (possibly rewritten) text and whether the message
+// should be kept; keep is false for a blocked user, a blocked pattern
+// match, or a "drop" rule match.
+func (c *Chain) Apply(scope Scope, username, text string) (result string, keep bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.blockedUsers[username] {
+		return "", false
+	}
+	for _, p := range c.blockedPatterns {
+		if p.MatchString(text) {
+			return "", false
+		}
+	}
+
+	for _, cr := range c.rules {
+		if cr.rule.Scope != scope && cr.rule.Scope != ScopeBoth {
+			continue
+		}
+		if !cr.pattern.MatchString(text) {
+			continue
+		}
+		switch cr.rule.Action {
+		case ActionDrop:
+			return "", false
+		case ActionRedact:
+			text = cr.pattern.ReplaceAllString(text, "[redacted]")
+		case ActionTag:
+			text = text + " [tagged]"
+		}
+	}
+	return text, true
+}
+
+// Rules returns the currently active rules, for inspection via
+// /api/v1/filters.
+func (c *Chain) Rules() []Rule {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	rules := make([]Rule, len(c.rules))
+	for i, cr := range c.rules {
+		rules[i] = cr.rule
+	}
+	return rules
+}
@@ -0,0 +1,74 @@
+package filter
+
+import "testing"
+
+func TestChainApply(t *testing.T) {
+	rules := []Rule{
+		{Match: "secret", Action: ActionRedact, Scope: ScopeBoth},
+		{Match: "spam", Action: ActionDrop, Scope: ScopeInbound},
+		{Match: "shout", Action: ActionTag, Scope: ScopeOutbound},
+	}
+	chain, err := NewChain([]string{"blocked-user"}, []string{"banned.*pattern"}, rules)
+	if err != nil {
+		t.Fatalf("failed to build chain: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		scope    Scope
+		username string
+		text     string
+		wantText string
+		wantKeep bool
+	}{
+		{"blocked user is dropped regardless of scope", ScopeInbound, "blocked-user", "hello", "", false},
+		{"blocked pattern is dropped", ScopeOutbound, "alice", "a banned secret pattern", "", false},
+		{"drop rule fires within its own scope", ScopeInbound, "alice", "this is spam", "", false},
+		{"drop rule does not fire outside its scope", ScopeOutbound, "alice", "this is spam", "this is spam", true},
+		{"redact rule applies to both scopes", ScopeInbound, "alice", "my secret is out", "my [redacted] is out", true},
+		{"tag rule fires within its own scope", ScopeOutbound, "alice", "shout it out", "shout it out [tagged]", true},
+		{"unmatched message passes through unchanged", ScopeBoth, "alice", "hello there", "hello there", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotText, gotKeep := chain.Apply(tt.scope, tt.username, tt.text)
+			if gotKeep != tt.wantKeep || gotText != tt.wantText {
+				t.Fatalf("Apply(%v, %q, %q) = (%q, %v), want (%q, %v)",
+					tt.scope, tt.username, tt.text, gotText, gotKeep, tt.wantText, tt.wantKeep)
+			}
+		})
+	}
+}
+
+func TestNewChainRejectsInvalidPattern(t *testing.T) {
+	if _, err := NewChain(nil, []string{"["}, nil); err == nil {
+		t.Fatal("expected an error for an invalid blocked pattern")
+	}
+}
+
+func TestNewChainRejectsInvalidRuleMatch(t *testing.T) {
+	if _, err := NewChain(nil, nil, []Rule{{Match: "(", Action: ActionDrop, Scope: ScopeBoth}}); err == nil {
+		t.Fatal("expected an error for an invalid rule match")
+	}
+}
+
+func TestChainReloadSwapsRuleset(t *testing.T) {
+	chain, err := NewChain([]string{"alice"}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to build chain: %v", err)
+	}
+	if _, keep := chain.Apply(ScopeBoth, "alice", "hi"); keep {
+		t.Fatal("expected alice to be blocked before reload")
+	}
+
+	if err := chain.Reload([]string{"bob"}, nil, nil); err != nil {
+		t.Fatalf("unexpected error reloading chain: %v", err)
+	}
+	if _, keep := chain.Apply(ScopeBoth, "alice", "hi"); !keep {
+		t.Fatal("expected alice to pass through after reload dropped her from the blocklist")
+	}
+	if _, keep := chain.Apply(ScopeBoth, "bob", "hi"); keep {
+		t.Fatal("expected bob to be blocked after reload")
+	}
+}
@@ -0,0 +1,55 @@
+package filter
+
+import (
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("http-chat-client/filter")
+
+// Watch watches path (the chat config file) and calls onChange whenever it
+// is written, so operators can update blocklists and rules without
+// restarting the process. It watches path's parent directory rather than
+// path itself, because editors and deploy tooling commonly replace a config
+// file by writing a new inode and renaming it over the old one; a watch on
+// the file directly would silently stop delivering events the moment that
+// happens, since the original inode is what's actually being watched. It
+// returns an error only if the watcher cannot be set up; errors reported
+// while watching are logged and watching goes on. It returns nil only once
+// the watcher's channels close; callers start it in its own goroutine.
+func Watch(path string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	dir := filepath.Dir(path)
+	if err := watcher.Add(dir); err != nil {
+		return err
+	}
+	name := filepath.Base(path)
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Base(event.Name) != name {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) != 0 {
+				log.Info().Msgf("Config file %q changed, reloading filters", path)
+				onChange()
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Error().Err(err).Msg("Filter config watcher error")
+		}
+	}
+}
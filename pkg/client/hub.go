@@ -0,0 +1,126 @@
+package client
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// EndPointWebSocket is where browsers open the live-update connection that
+// replaces the old meta-refresh iframe polling.
+const EndPointWebSocket = "/ws"
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// The client and server are expected to run on the same origin in the
+	// common deployment, so CheckOrigin is left nil and the upgrader's
+	// default same-origin check applies; relax this if that stops being true.
+}
+
+// wsEvent is the frame shape pushed to every subscriber: {"type": "...", "data": ...}
+type wsEvent struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+const (
+	wsEventTypeMessage = "message"
+	wsEventTypeUsers   = "users"
+)
+
+// subscriber is a single browser connection registered with the hub.
+type subscriber struct {
+	conn *websocket.Conn
+	send chan wsEvent
+}
+
+// hub keeps the registry of connected subscribers and fans out events to
+// them. A slow consumer never blocks the others: its send channel is
+// bounded, and a subscriber that can't keep up gets dropped.
+type hub struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]bool
+}
+
+func newHub() *hub {
+	return &hub{subscribers: make(map[*subscriber]bool)}
+}
+
+var chatHub = newHub()
+
+const subscriberSendBuffer = 16
+
+func (h *hub) register(s *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subscribers[s] = true
+}
+
+func (h *hub) unregister(s *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[s]; ok {
+		delete(h.subscribers, s)
+		close(s.send)
+	}
+}
+
+// broadcast fans an event out to every subscriber. A subscriber whose send
+// buffer is full is considered a slow consumer and is dropped rather than
+// allowed to stall the broadcast.
+func (h *hub) broadcast(event wsEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subscribers {
+		select {
+		case s.send <- event:
+		default:
+			log.Error().Msg("Dropping slow WebSocket subscriber")
+			delete(h.subscribers, s)
+			close(s.send)
+			_ = s.conn.Close()
+		}
+	}
+}
+
+// writePump drains a subscriber's send channel onto its websocket
+// connection. It closes the connection and returns as soon as a write fails
+// or the channel is closed.
+func (s *subscriber) writePump() {
+	defer s.conn.Close()
+	for event := range s.send {
+		if err := s.conn.WriteJSON(event); err != nil {
+			log.Error().Err(err).Msg("Failed to write WebSocket frame")
+			return
+		}
+	}
+}
+
+// readPump discards inbound traffic but keeps reading so that the
+// connection's close/ping control frames are handled; it returns (and
+// unregisters the subscriber) the moment the browser goes away.
+func (s *subscriber) readPump(h *hub) {
+	defer h.unregister(s)
+	for {
+		if _, _, err := s.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// HandlerWebSocket upgrades the connection and registers it with the hub.
+// The ticker goroutine started in NewChatClient pushes message and user
+// events to every registered subscriber as they change.
+func HandlerWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to upgrade to WebSocket")
+		return
+	}
+	s := &subscriber{conn: conn, send: make(chan wsEvent, subscriberSendBuffer)}
+	chatHub.register(s)
+	go s.writePump()
+	s.readPump(chatHub)
+}
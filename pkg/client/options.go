@@ -0,0 +1,99 @@
+package client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Transport abstracts every call NewChatClient makes against the upstream
+// chat server. The default Options wires up the plain-HTTP implementation
+// this package has always used; tests can inject an in-memory Transport,
+// and future protocols (gRPC, a file-based replay transport, ...) can
+// implement it without touching the handlers.
+type Transport interface {
+	SendMessage(msg Message) error
+	GetMessages() ([]Message, error)
+	SendPing(ping Ping) error
+	GetActiveUsers() ([]*User, error)
+}
+
+// RetryPolicy controls how the default Transport retries a failed request
+// against the upstream chat server.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// defaultRetryPolicy preserves today's behavior of not retrying at all.
+var defaultRetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+// Options configures NewChatClient. Build one with the With* functions
+// below rather than constructing it directly.
+type Options struct {
+	HTTPClient  *http.Client
+	Transport   Transport
+	TLSConfig   *tls.Config
+	AuthToken   string
+	RetryPolicy RetryPolicy
+	UserAgent   string
+	Logger      zerolog.Logger
+}
+
+// Option mutates an Options in place.
+type Option func(*Options)
+
+// defaultOptions returns the Options NewChatClient used to hardcode:
+// a bare *http.Client, no TLS config, no auth token, no retries.
+func defaultOptions() *Options {
+	return &Options{
+		HTTPClient:  &http.Client{},
+		TLSConfig:   nil,
+		RetryPolicy: defaultRetryPolicy,
+		UserAgent:   "http-chat-client",
+		Logger:      log,
+	}
+}
+
+// WithHTTPClient overrides the *http.Client the default Transport sends
+// requests with.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *Options) { o.HTTPClient = httpClient }
+}
+
+// WithTransport replaces the Transport entirely, bypassing HTTPClient,
+// TLSConfig, AuthToken, and RetryPolicy.
+func WithTransport(transport Transport) Option {
+	return func(o *Options) { o.Transport = transport }
+}
+
+// WithTLSConfig sets the TLS config used by the default Transport's
+// *http.Client.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(o *Options) { o.TLSConfig = tlsConfig }
+}
+
+// WithAuthToken sets a bearer token the default Transport attaches to
+// every request against the upstream chat server.
+func WithAuthToken(token string) Option {
+	return func(o *Options) { o.AuthToken = token }
+}
+
+// WithRetryPolicy overrides how the default Transport retries a failed
+// request.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *Options) { o.RetryPolicy = policy }
+}
+
+// WithUserAgent sets the User-Agent header the default Transport sends.
+func WithUserAgent(userAgent string) Option {
+	return func(o *Options) { o.UserAgent = userAgent }
+}
+
+// WithLogger overrides the logger NewChatClient and its Transport log
+// through.
+func WithLogger(logger zerolog.Logger) Option {
+	return func(o *Options) { o.Logger = logger }
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// httpTransport is the default Transport: plain HTTP against a single
+// ChatServerFQDN, the behavior this package has always had.
+type httpTransport struct {
+	httpClient *http.Client
+	authToken  string
+	userAgent  string
+	retry      RetryPolicy
+}
+
+func newHTTPTransport(o *Options) *httpTransport {
+	httpClient := o.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	if o.TLSConfig != nil {
+		httpClient.Transport = &http.Transport{TLSClientConfig: o.TLSConfig}
+	}
+	return &httpTransport{
+		httpClient: httpClient,
+		authToken:  o.AuthToken,
+		userAgent:  o.UserAgent,
+		retry:      o.RetryPolicy,
+	}
+}
+
+// doWithRetry builds and sends a request up to t.retry.MaxAttempts times,
+// sleeping t.retry.Backoff between attempts. build is called again for
+// every attempt so each one gets a fresh, unconsumed request body.
+func (t *httpTransport) doWithRetry(build func() (*http.Request, error)) (*http.Response, error) {
+	attempts := t.retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		req, err := build()
+		if err != nil {
+			return nil, err
+		}
+		if t.authToken != "" {
+			req.Header.Set("Authorization", "Bearer "+t.authToken)
+		}
+		if t.userAgent != "" {
+			req.Header.Set("User-Agent", t.userAgent)
+		}
+
+		resp, err := t.httpClient.Do(req)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+		if attempt < attempts-1 && t.retry.Backoff > 0 {
+			time.Sleep(t.retry.Backoff)
+		}
+	}
+	return nil, lastErr
+}
+
+func (t *httpTransport) SendMessage(msg Message) error {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("http://%s:%d/messages", Config.ChatServerFQDN, Config.ChatServerPort)
+	resp, err := t.doWithRetry(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(jsonBytes))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to POST message to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to POST message to %s, status code: %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (t *httpTransport) GetMessages() ([]Message, error) {
+	url := fmt.Sprintf("http://%s:%d/messages", Config.ChatServerFQDN, Config.ChatServerPort)
+	resp, err := t.doWithRetry(func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, url, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get messages from %s, status code: %d", url, resp.StatusCode)
+	}
+	var messages []Message
+	if err := json.NewDecoder(resp.Body).Decode(&messages); err != nil {
+		return nil, fmt.Errorf("failed to decode messages from %s: %w", url, err)
+	}
+	return messages, nil
+}
+
+func (t *httpTransport) SendPing(ping Ping) error {
+	jsonBytes, err := json.Marshal(ping)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("http://%s:%d/ping", Config.ChatServerFQDN, Config.ChatServerPort)
+	resp, err := t.doWithRetry(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(jsonBytes))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send a ping to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to send ping to %s, status code: %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (t *httpTransport) GetActiveUsers() ([]*User, error) {
+	url := fmt.Sprintf("http://%s:%d/users", Config.ChatServerFQDN, Config.ChatServerPort)
+	resp, err := t.doWithRetry(func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, url, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active users from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get active users from %s, status code: %d", url, resp.StatusCode)
+	}
+	var users []*User
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return nil, fmt.Errorf("failed to decode active users from %s: %w", url, err)
+	}
+	return users, nil
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/draychev/http-chat-client/pkg/client/filter"
+)
+
+// mockTransport is a Transport that never talks to a real chat server, so
+// handlers and the filter chain can be exercised without one.
+type mockTransport struct {
+	sendErr     error
+	sentMessage Message
+	messages    []Message
+	getErr      error
+	users       []*User
+	usersErr    error
+}
+
+func (m *mockTransport) SendMessage(msg Message) error {
+	m.sentMessage = msg
+	return m.sendErr
+}
+
+func (m *mockTransport) GetMessages() ([]Message, error) {
+	return m.messages, m.getErr
+}
+
+func (m *mockTransport) SendPing(ping Ping) error {
+	return nil
+}
+
+func (m *mockTransport) GetActiveUsers() ([]*User, error) {
+	return m.users, m.usersErr
+}
+
+func withMockTransport(t *testing.T, mock *mockTransport) {
+	t.Helper()
+	original := activeTransport
+	activeTransport = mock
+	t.Cleanup(func() { activeTransport = original })
+}
+
+func TestSendMessageUsesActiveTransport(t *testing.T) {
+	mock := &mockTransport{}
+	withMockTransport(t, mock)
+
+	originalUsername := EnvVarUserName
+	EnvVarUserName = "alice"
+	t.Cleanup(func() { EnvVarUserName = originalUsername })
+
+	if err := SendMessage("hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.sentMessage.Username != "alice" || mock.sentMessage.Message != "hello" {
+		t.Fatalf("unexpected message sent to transport: %+v", mock.sentMessage)
+	}
+}
+
+func TestSendMessagePropagatesTransportError(t *testing.T) {
+	withMockTransport(t, &mockTransport{sendErr: errors.New("upstream unavailable")})
+
+	if err := SendMessage("hello"); err == nil {
+		t.Fatal("expected SendMessage to propagate the transport error")
+	}
+}
+
+func TestGetMessagesAppliesInboundFilters(t *testing.T) {
+	withMockTransport(t, &mockTransport{messages: []Message{
+		{Username: "blocked-user", Message: "should be dropped"},
+		{Username: "alice", Message: "hi there"},
+	}})
+
+	chain, err := filter.NewChain([]string{"blocked-user"}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to build filter chain: %v", err)
+	}
+	original := filterChain
+	filterChain = chain
+	t.Cleanup(func() { filterChain = original })
+
+	got := GetMessages()
+	if len(got) != 1 || got[0].Username != "alice" {
+		t.Fatalf("expected only alice's message to survive filtering, got %+v", got)
+	}
+}
+
+func TestGetActiveUsersPropagatesTransportResult(t *testing.T) {
+	want := []*User{{Username: "alice"}}
+	withMockTransport(t, &mockTransport{users: want})
+
+	got := GetActiveUsers()
+	if len(got) != 1 || got[0].Username != "alice" {
+		t.Fatalf("unexpected users returned: %+v", got)
+	}
+}